@@ -0,0 +1,46 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ShuBo6/cloudquery/cmd/utils"
+	"github.com/ShuBo6/cloudquery/pkg/errors"
+	"github.com/ShuBo6/cloudquery/pkg/ui/console"
+	"github.com/spf13/cobra"
+)
+
+const backupShort = "Dump the CloudQuery schemas for one or more providers into a versioned archive"
+
+func newCmdDbBackup() *cobra.Command {
+	var output string
+	var tables []string
+
+	cmd := &cobra.Command{
+		Use:   "backup [providers,...]",
+		Short: backupShort,
+		Long:  backupShort,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := console.CreateClient(cmd.Context(), utils.GetConfigFile(), false, nil, utils.InstanceId)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create backup file %q: %w", output, err)
+			}
+			defer f.Close()
+
+			diags := c.BackupDatabase(cmd.Context(), f, args, tables)
+			errors.CaptureDiagnostics(diags, map[string]string{"command": "db_backup"})
+			if diags.HasErrors() {
+				return fmt.Errorf("failed to backup database %w", diags)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&output, "output", "cloudquery.backup", "path to write the backup archive to")
+	cmd.Flags().StringSliceVar(&tables, "tables", nil, "restrict the backup to these tables; defaults to every table in the selected providers")
+	return cmd
+}
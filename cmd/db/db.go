@@ -0,0 +1,17 @@
+package db
+
+import "github.com/spf13/cobra"
+
+const dbShort = "Backup and restore the CloudQuery database"
+
+// NewCmdDb returns the `cloudquery db` command group.
+func NewCmdDb() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: dbShort,
+		Long:  dbShort,
+	}
+	cmd.AddCommand(newCmdDbBackup())
+	cmd.AddCommand(newCmdDbRestore())
+	return cmd
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ShuBo6/cloudquery/cmd/utils"
+	"github.com/ShuBo6/cloudquery/pkg/errors"
+	"github.com/ShuBo6/cloudquery/pkg/ui/console"
+	"github.com/spf13/cobra"
+)
+
+const restoreShort = "Reload a CloudQuery backup archive produced by `cloudquery db backup`"
+
+func newCmdDbRestore() *cobra.Command {
+	var input string
+
+	cmd := &cobra.Command{
+		Use:   "restore [providers,...]",
+		Short: restoreShort,
+		Long:  restoreShort,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := console.CreateClient(cmd.Context(), utils.GetConfigFile(), false, nil, utils.InstanceId)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(input)
+			if err != nil {
+				return fmt.Errorf("failed to open backup file %q: %w", input, err)
+			}
+			defer f.Close()
+
+			diags := c.RestoreDatabase(cmd.Context(), f, args)
+			errors.CaptureDiagnostics(diags, map[string]string{"command": "db_restore"})
+			if diags.HasErrors() {
+				return fmt.Errorf("failed to restore database %w", diags)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&input, "input", "cloudquery.backup", "path to the backup archive to restore from")
+	return cmd
+}
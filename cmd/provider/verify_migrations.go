@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/ShuBo6/cloudquery/cmd/utils"
+	"github.com/ShuBo6/cloudquery/pkg/errors"
+	"github.com/ShuBo6/cloudquery/pkg/ui/console"
+	"github.com/spf13/cobra"
+)
+
+const verifyMigrationsShort = "Check that a provider's incremental migrations produce the same schema as a fresh sync"
+
+func newCmdProviderVerifyMigrations() *cobra.Command {
+	var migratedDSN string
+	var freshDSN string
+
+	cmd := &cobra.Command{
+		Use:   "verify-migrations <provider>",
+		Short: verifyMigrationsShort,
+		Long:  verifyMigrationsShort,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			provider := args[0]
+
+			c, err := console.CreateClient(cmd.Context(), utils.GetConfigFile(), false, nil, utils.InstanceId)
+			if err != nil {
+				return err
+			}
+
+			diff, diags := c.VerifyProviderMigrations(cmd.Context(), provider, migratedDSN, freshDSN)
+			errors.CaptureDiagnostics(diags, map[string]string{"command": "provider_verify_migrations"})
+			if diags.HasErrors() {
+				return fmt.Errorf("failed to verify migrations for provider %q %w", provider, diags)
+			}
+			if diff != "" {
+				return fmt.Errorf("schema produced by incremental migrations differs from a fresh sync for provider %q:\n%s", provider, diff)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&migratedDSN, "migrated-dsn", "", "scratch database to apply incremental migrations into (required)")
+	cmd.Flags().StringVar(&freshDSN, "fresh-dsn", "", "scratch database to run a fresh sync into (required)")
+	_ = cmd.MarkFlagRequired("migrated-dsn")
+	_ = cmd.MarkFlagRequired("fresh-dsn")
+	return cmd
+}
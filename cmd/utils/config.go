@@ -0,0 +1,9 @@
+package utils
+
+// InstanceId identifies this CLI invocation when diagnostics are reported.
+var InstanceId = "cli"
+
+// GetConfigFile returns the path to the cloudquery.yml config file used for this invocation.
+func GetConfigFile() string {
+	return "cloudquery.yml"
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// RequiredProvider describes one entry under `cloudquery.providers` in cloudquery.yml.
+type RequiredProvider struct {
+	Name   string  `yaml:"name"`
+	Source *string `yaml:"source,omitempty"`
+}
+
+// Connection describes how to reach the CloudQuery database.
+type Connection struct {
+	DSN            string   `yaml:"dsn"`
+	Replicas       []string `yaml:"replicas,omitempty"`
+	ReadStrictMode bool     `yaml:"read_strict_mode,omitempty"`
+}
+
+// CloudQuery is the `cloudquery:` block of cloudquery.yml.
+type CloudQuery struct {
+	Connection Connection          `yaml:"connection"`
+	Providers  []*RequiredProvider `yaml:"providers"`
+}
+
+// Config is the parsed contents of cloudquery.yml.
+type Config struct {
+	CloudQuery CloudQuery `yaml:"cloudquery"`
+}
+
+// Load reads and parses the cloudquery.yml file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
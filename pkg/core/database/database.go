@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ShuBo6/cloudquery/pkg/core/database/model"
+	"github.com/ShuBo6/cloudquery/pkg/core/database/mysql"
+	"github.com/ShuBo6/cloudquery/pkg/core/database/postgres"
+	"github.com/ShuBo6/cloudquery/pkg/core/database/sqlite"
+)
+
+// Executor is the interface every database backend must implement to support the sync/fetch/query
+// workflow. postgres.Executor is the reference implementation; New dispatches to the right backend based
+// on the DSN scheme.
+type Executor interface {
+	// Validate checks connectivity and backend-specific version/compatibility requirements.
+	Validate(ctx context.Context) (bool, error)
+	// Info returns information about the connected database instance.
+	Info(ctx context.Context) (model.DatabaseInfo, error)
+	// Identifier returns a stable identifier for the connected database instance, if one is available.
+	Identifier(ctx context.Context) (string, bool)
+	// CreateProviderSchema creates the namespace a provider's tables live in, if it doesn't already exist.
+	CreateProviderSchema(ctx context.Context, provider string) error
+	// DropProviderSchema drops the namespace a provider's tables live in, along with everything in it.
+	DropProviderSchema(ctx context.Context, provider string) error
+}
+
+// New resolves dsn's scheme and returns the matching Executor implementation. SyncProviders and the rest
+// of the codebase should go through New rather than constructing a specific backend directly, so that
+// users who don't want to run Postgres can still point CloudQuery at sqlite:// or mysql://.
+func New(dsn string) (Executor, error) {
+	// Legacy libpq-style DSNs ("host=... user=... dbname=...") have no scheme to dispatch on; they've
+	// always meant postgres, so keep routing them there instead of rejecting them as "unsupported".
+	if !strings.Contains(dsn, "://") {
+		return postgres.New(dsn), nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database dsn: %w", err)
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return postgres.New(dsn), nil
+	case "sqlite":
+		return sqlite.New(dsn), nil
+	case "mysql":
+		return mysql.New(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database scheme %q (expected one of postgres, sqlite, mysql)", u.Scheme)
+	}
+}
@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShuBo6/cloudquery/pkg/core/database/model"
+)
+
+// errNotImplemented is returned by every Executor method until the MySQL backend is filled in.
+var errNotImplemented = errors.New("the mysql database backend is not implemented yet")
+
+// Executor is a stub implementation of database.Executor for mysql:// DSNs. It exists so the factory
+// recognizes the scheme; sync/fetch/query support will follow in a later change.
+type Executor struct {
+	dsn string
+}
+
+// New returns a stub MySQL Executor for the given dsn.
+func New(dsn string) *Executor {
+	return &Executor{dsn: dsn}
+}
+
+func (e *Executor) Validate(context.Context) (bool, error) {
+	return false, errNotImplemented
+}
+
+func (e *Executor) Info(context.Context) (model.DatabaseInfo, error) {
+	return model.DatabaseInfo{}, errNotImplemented
+}
+
+func (e *Executor) Identifier(context.Context) (string, bool) {
+	return "", false
+}
+
+func (e *Executor) CreateProviderSchema(context.Context, string) error {
+	return errNotImplemented
+}
+
+func (e *Executor) DropProviderSchema(context.Context, string) error {
+	return errNotImplemented
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// archiveFormatVersion is bumped whenever the layout of a backup archive changes in a way that would
+// break older Restore implementations.
+const archiveFormatVersion = 1
+
+// ProviderBackupSpec identifies a single provider schema to include in a backup archive, together with
+// the schema-migration version the provider had applied when the backup was taken.
+type ProviderBackupSpec struct {
+	// Name is the provider schema name, e.g. "aws".
+	Name string
+	// MigrationVersion is the schema-migration version recorded for this provider at backup time.
+	MigrationVersion string
+	// Tables restricts the dump to these tables within the schema. Empty dumps every table.
+	Tables []string
+}
+
+// BackupOptions controls which provider schemas Backup writes into the archive.
+type BackupOptions struct {
+	Providers []ProviderBackupSpec
+}
+
+// RestoreOptions controls which provider schemas Restore loads back into the database.
+type RestoreOptions struct {
+	// Providers restricts restore to the given provider schemas. An empty slice restores every provider
+	// found in the archive.
+	Providers []string
+	// CurrentMigrationVersions maps provider name to the migration version this CLI currently expects for
+	// that provider. Restore refuses to load a provider whose archived version doesn't match.
+	CurrentMigrationVersions map[string]string
+}
+
+// archiveManifest is stored as manifest.json at the root of every backup archive.
+type archiveManifest struct {
+	FormatVersion int               `json:"format_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Providers     map[string]string `json:"providers"` // provider name -> migration version at backup time
+}
+
+// Backup dumps the schemas named in opts.Providers into a versioned, gzip-compressed tar archive written
+// to w. Each provider's migration version is embedded in the archive manifest so Restore can refuse to
+// load it into a database whose provider migrations don't match.
+func (e *Executor) Backup(ctx context.Context, w io.Writer, opts BackupOptions) error {
+	manifest := archiveManifest{
+		FormatVersion: archiveFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Providers:     make(map[string]string, len(opts.Providers)),
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, p := range opts.Providers {
+		dump, err := e.dumpProviderSchema(ctx, p.Name, p.Tables)
+		if err != nil {
+			return fmt.Errorf("failed to dump schema for provider %q: %w", p.Name, err)
+		}
+		if err := writeTarEntry(tw, p.Name+".sql", dump); err != nil {
+			return fmt.Errorf("failed to write archive entry for provider %q: %w", p.Name, err)
+		}
+		manifest.Providers[p.Name] = p.MigrationVersion
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, "manifest.json", manifestBytes); err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize backup archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// Restore reads a backup archive produced by Backup from r and reloads the selected provider schemas into
+// the database. Providers are visited in no fixed order. At the first provider whose archived migration
+// version doesn't match opts.CurrentMigrationVersions[name], Restore returns an error without loading that
+// provider or any provider after it, since loading it could leave the database in a state no installed
+// provider understands; providers restored before that point stay loaded.
+func (e *Executor) Restore(ctx context.Context, r io.Reader, opts RestoreOptions) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read backup archive: %w", err)
+		}
+		buf, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read archive entry %q: %w", hdr.Name, err)
+		}
+		entries[hdr.Name] = buf
+	}
+
+	manifestBytes, ok := entries["manifest.json"]
+	if !ok {
+		return fmt.Errorf("backup archive is missing manifest.json")
+	}
+	var manifest archiveManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("failed to decode archive manifest: %w", err)
+	}
+	if manifest.FormatVersion != archiveFormatVersion {
+		return fmt.Errorf("unsupported backup archive format version %d (expected %d)", manifest.FormatVersion, archiveFormatVersion)
+	}
+
+	wanted := make(map[string]bool, len(opts.Providers))
+	for _, name := range opts.Providers {
+		wanted[name] = true
+	}
+
+	for name, archivedVersion := range manifest.Providers {
+		if len(wanted) > 0 && !wanted[name] {
+			continue
+		}
+		if current, ok := opts.CurrentMigrationVersions[name]; ok && current != archivedVersion {
+			return fmt.Errorf("refusing to restore provider %q: archive was built against migration version %q but this CLI expects %q; run `cloudquery provider sync` or use a matching CLI version", name, archivedVersion, current)
+		}
+		dump, ok := entries[name+".sql"]
+		if !ok {
+			return fmt.Errorf("backup archive manifest references provider %q but its dump is missing", name)
+		}
+		if err := e.loadProviderSchema(ctx, dump); err != nil {
+			return fmt.Errorf("failed to restore schema for provider %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (e *Executor) dumpProviderSchema(ctx context.Context, schema string, tables []string) ([]byte, error) {
+	// Backups are read-only, so route the dump to a replica (and check it isn't stale) when one is
+	// configured, rather than always hitting the primary.
+	dsn, err := e.ReadDSN(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick a read source for schema %q: %w", schema, err)
+	}
+
+	args := []string{"--dbname=" + dsn, "--schema=" + schema, "--no-owner"}
+	for _, t := range tables {
+		args = append(args, "--table="+schema+"."+t)
+	}
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pg_dump", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pg_dump failed: %w: %s", err, stderr.String())
+	}
+	return out.Bytes(), nil
+}
+
+func (e *Executor) loadProviderSchema(ctx context.Context, dump []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "psql", "--dbname="+e.dsn, "--single-transaction", "--set", "ON_ERROR_STOP=1")
+	cmd.Stdin = bytes.NewReader(dump)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("psql restore failed: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0o600,
+		Size: int64(len(data)),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ShuBo6/cloudquery/pkg/core/database/model"
@@ -14,16 +15,29 @@ import (
 )
 
 type Executor struct {
-	dsn  string
-	dbId string
-	info model.DatabaseInfo
+	dsn    string
+	dbId   string
+	info   model.DatabaseInfo
+	config Config
+
+	mu         sync.RWMutex
+	primaryLSN string
+	replicaIdx uint32
 }
 
 var MinPostgresVersion = version.Must(version.NewVersion("10.0"))
 
+// New returns a postgres Executor with no read replicas, for the common single-DSN case.
 func New(dsn string) *Executor {
+	return NewWithConfig(Config{Primary: dsn})
+}
+
+// NewWithConfig returns a postgres Executor configured with a primary DSN and, optionally, one or more
+// read replicas. All schema/DDL and writes go through the primary; see ReadPool for how reads are routed.
+func NewWithConfig(cfg Config) *Executor {
 	return &Executor{
-		dsn: dsn,
+		dsn:    cfg.Primary,
+		config: cfg,
 	}
 }
 
@@ -48,6 +62,10 @@ func (e *Executor) Validate(ctx context.Context) (bool, error) {
 		return true, err
 	}
 
+	if err := e.ValidateMigrationCompatibility(ctx, pool, e.config.ProviderMigrations); err != nil {
+		return true, err
+	}
+
 	return true, dbIdErr
 }
 
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// schemaMigrationsTable is created and owned by core. It records the schema-migration version each
+// installed provider has applied, so a CLI binary can tell whether it understands the schema it's
+// talking to before it tries to sync or query it.
+const schemaMigrationsTable = "cloudquery_schema_migrations"
+
+// MigrationWindow describes the inclusive range of schema-migration versions a provider binary is
+// compatible with. Values come from the plugin registry's MinCompatibleMigration/MaxCompatibleMigration
+// constants, surfaced through ParseProviderSource. Versions are compared as zero-padded, lexically
+// ordered strings, matching the provider migration directory naming convention.
+type MigrationWindow struct {
+	Min string
+	Max string
+}
+
+type migrationRow struct {
+	Provider string `db:"provider"`
+	Version  string `db:"version"`
+}
+
+// ProviderMigrationVersions reads the schema-migration version recorded in schemaMigrationsTable for
+// every provider installed in the database.
+func (e *Executor) ProviderMigrationVersions(ctx context.Context, pool *pgxpool.Pool) (map[string]string, error) {
+	api, err := e.scanAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []migrationRow
+	if err := api.Select(ctx, pool, &rows, fmt.Sprintf(`SELECT provider, version FROM %s`, schemaMigrationsTable)); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schemaMigrationsTable, err)
+	}
+
+	versions := make(map[string]string, len(rows))
+	for _, row := range rows {
+		versions[row.Provider] = row.Version
+	}
+	return versions, nil
+}
+
+// ValidateMigrationCompatibility checks the schema-migration version recorded for each provider in
+// windows against that provider's compatible range. It returns an actionable error for the first provider
+// found outside its window; a provider that has never been synced is skipped since there's nothing to
+// validate yet.
+func (e *Executor) ValidateMigrationCompatibility(ctx context.Context, pool *pgxpool.Pool, windows map[string]MigrationWindow) error {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	versions, err := e.ProviderMigrationVersions(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	for provider, window := range windows {
+		version, ok := versions[provider]
+		if !ok {
+			continue
+		}
+		if version < window.Min || version > window.Max {
+			return fmt.Errorf(
+				"provider %q has schema-migration version %s installed, but this CLI binary supports %s-%s; "+
+					"run `cloudquery provider sync %s` to bring the schema up to date, or install a CLI version that supports %s",
+				provider, version, window.Min, window.Max, provider, version,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package migrationtest compares the schema a provider's incremental migrations produce against the
+// schema produced by a fresh `provider sync`, so provider authors can catch drift between the two paths
+// before releasing a new provider version.
+package migrationtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SyncFunc performs a fresh provider sync against dsn, the same way `cloudquery provider sync` does.
+type SyncFunc func(ctx context.Context, dsn, provider string) error
+
+// MigrateFunc applies provider's incremental migrations up to HEAD against dsn.
+type MigrateFunc func(ctx context.Context, dsn, provider string) error
+
+// Options configures a Diff run. MigratedDSN and FreshDSN must point at empty scratch databases.
+type Options struct {
+	Provider    string
+	MigratedDSN string
+	FreshDSN    string
+	Migrate     MigrateFunc
+	Sync        SyncFunc
+}
+
+// Diff applies opts.Migrate into opts.MigratedDSN and opts.Sync into opts.FreshDSN, dumps both schemas
+// with `pg_dump --schema-only`, and returns a diff of the two. An empty result means the schemas match.
+func Diff(ctx context.Context, opts Options) (string, error) {
+	if err := opts.Migrate(ctx, opts.MigratedDSN, opts.Provider); err != nil {
+		return "", fmt.Errorf("failed to apply incremental migrations for provider %q: %w", opts.Provider, err)
+	}
+	if err := opts.Sync(ctx, opts.FreshDSN, opts.Provider); err != nil {
+		return "", fmt.Errorf("failed to run a fresh sync for provider %q: %w", opts.Provider, err)
+	}
+
+	migrated, err := schemaOnlyDump(ctx, opts.MigratedDSN, opts.Provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to dump migrated schema for provider %q: %w", opts.Provider, err)
+	}
+	fresh, err := schemaOnlyDump(ctx, opts.FreshDSN, opts.Provider)
+	if err != nil {
+		return "", fmt.Errorf("failed to dump fresh-sync schema for provider %q: %w", opts.Provider, err)
+	}
+
+	return diffLines(migrated, fresh), nil
+}
+
+func schemaOnlyDump(ctx context.Context, dsn, schema string) (string, error) {
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "pg_dump", "--dbname="+dsn, "--schema-only", "--schema="+schema, "--no-owner")
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("pg_dump failed: %w: %s", err, stderr.String())
+	}
+	return out.String(), nil
+}
+
+// diffLines returns a minimal line-level diff between a and b: a line that appears more times in a than
+// in b is reported as "-" for the excess occurrences, and vice versa for "+". Counting occurrences (rather
+// than just set membership) matters here because a duplicated statement - e.g. a migration that emits a
+// CREATE INDEX twice where a fresh sync only emits it once - is exactly the class of drift this harness
+// exists to catch, and it would be invisible to a membership-only comparison. It isn't a unified diff, but
+// it's enough to point a provider author at the statements that drifted between the two sync paths.
+func diffLines(a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+	aCounts := make(map[string]int, len(aLines))
+	for _, l := range aLines {
+		aCounts[l]++
+	}
+	bCounts := make(map[string]int, len(bLines))
+	for _, l := range bLines {
+		bCounts[l]++
+	}
+
+	var buf strings.Builder
+	seen := make(map[string]bool, len(aCounts)+len(bCounts))
+	report := func(l string) {
+		if seen[l] {
+			return
+		}
+		seen[l] = true
+		for i := bCounts[l]; i < aCounts[l]; i++ {
+			fmt.Fprintf(&buf, "-%s\n", l)
+		}
+		for i := aCounts[l]; i < bCounts[l]; i++ {
+			fmt.Fprintf(&buf, "+%s\n", l)
+		}
+	}
+	for _, l := range aLines {
+		report(l)
+	}
+	for _, l := range bLines {
+		report(l)
+	}
+	return buf.String()
+}
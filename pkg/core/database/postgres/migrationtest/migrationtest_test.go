@@ -0,0 +1,37 @@
+package migrationtest
+
+import "testing"
+
+func TestDiffLines_Identical(t *testing.T) {
+	schema := "CREATE TABLE foo (id int);\nCREATE INDEX foo_idx ON foo (id);"
+	if diff := diffLines(schema, schema); diff != "" {
+		t.Fatalf("expected no diff for identical schemas, got:\n%s", diff)
+	}
+}
+
+func TestDiffLines_DetectsDuplicatedStatement(t *testing.T) {
+	// A migration bug duplicating a CREATE INDEX is invisible to a membership-only diff: the statement
+	// is present in both schemas, just a different number of times.
+	fresh := "CREATE TABLE foo (id int);\nCREATE INDEX foo_idx ON foo (id);"
+	migrated := "CREATE TABLE foo (id int);\nCREATE INDEX foo_idx ON foo (id);\nCREATE INDEX foo_idx ON foo (id);"
+
+	diff := diffLines(migrated, fresh)
+	if diff == "" {
+		t.Fatal("expected a diff for a duplicated CREATE INDEX statement, got none")
+	}
+	want := "-CREATE INDEX foo_idx ON foo (id);\n"
+	if diff != want {
+		t.Fatalf("diff = %q, want %q", diff, want)
+	}
+}
+
+func TestDiffLines_DetectsMissingAndExtraStatements(t *testing.T) {
+	migrated := "CREATE TABLE foo (id int);"
+	fresh := "CREATE TABLE foo (id int);\nCREATE TABLE bar (id int);"
+
+	diff := diffLines(migrated, fresh)
+	want := "+CREATE TABLE bar (id int);\n"
+	if diff != want {
+		t.Fatalf("diff = %q, want %q", diff, want)
+	}
+}
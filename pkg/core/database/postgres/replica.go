@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	sdkpg "github.com/cloudquery/cq-provider-sdk/database/postgres"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Config configures a postgres Executor with an optional set of read replicas.
+type Config struct {
+	// Primary is the DSN of the read/write primary. All schema/DDL and writes go here.
+	Primary string
+	// Replicas are read-only replica DSNs. Read-only queries (policy execution, `policy run`, `provider
+	// fetch --dry-run` diffs) are routed here, round-robin, when set.
+	Replicas []string
+	// ReadStrictMode pins each read session to the latest WAL position observed on the primary and fails
+	// with a RevisionUnavailableError if the chosen replica hasn't replayed that far yet, instead of
+	// silently serving stale data.
+	ReadStrictMode bool
+	// ProviderMigrations maps provider name to the schema-migration version range this CLI binary
+	// supports for that provider. Validate rejects a database whose recorded migration version for a
+	// configured provider falls outside its window.
+	ProviderMigrations map[string]MigrationWindow
+	// IgnoreUnknownColumns lets reads tolerate columns the running CLI doesn't know about, so a
+	// mixed-version deployment (an older CLI querying a database a newer provider worker populated)
+	// doesn't fail an entire fetch or policy query over one unrecognized column.
+	IgnoreUnknownColumns bool
+}
+
+// RevisionUnavailableError is returned by ReadPool when ReadStrictMode is enabled and the chosen replica
+// has not replayed the WAL position that was current on the primary as of the last write. Callers should
+// retry against the primary rather than read stale data.
+type RevisionUnavailableError struct {
+	Replica    string
+	WantLSN    string
+	ReplicaLSN string
+}
+
+func (e *RevisionUnavailableError) Error() string {
+	return fmt.Sprintf("replica %q has not caught up to LSN %s (currently at %s)", e.Replica, e.WantLSN, e.ReplicaLSN)
+}
+
+// WritePool connects to the primary. It does not, by itself, record a WAL position: callers must call
+// RecordWriteLSN once their write has committed, so a subsequent strict ReadPool call only considers a
+// replica caught up to writes it has actually replicated.
+func (e *Executor) WritePool(ctx context.Context) (*pgxpool.Pool, error) {
+	return sdkpg.Connect(ctx, e.dsn)
+}
+
+// RecordWriteLSN stores the primary's current WAL position for later comparison by ReadDSN/ReadPool in
+// ReadStrictMode. Callers must invoke this with pool only after their write has committed - recording it
+// beforehand would let a replica be considered caught up to a write it hasn't replicated yet.
+func (e *Executor) RecordWriteLSN(ctx context.Context, pool *pgxpool.Pool) error {
+	if len(e.config.Replicas) == 0 {
+		return nil
+	}
+
+	lsn, err := currentWALLSN(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("failed to record primary WAL position: %w", err)
+	}
+	e.mu.Lock()
+	e.primaryLSN = lsn
+	e.mu.Unlock()
+	return nil
+}
+
+// ReadPool returns a connection pool for read-only work: a replica when one is configured (picked
+// round-robin), the primary otherwise. When Config.ReadStrictMode is set, it fails with a
+// RevisionUnavailableError if the chosen replica hasn't replayed the WAL position recorded by the last
+// RecordWriteLSN call. Policy execution, `cloudquery policy run`, and `provider fetch --dry-run` diffs should
+// read through this instead of connecting to e.dsn directly.
+func (e *Executor) ReadPool(ctx context.Context) (*pgxpool.Pool, error) {
+	dsn, err := e.ReadDSN(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sdkpg.Connect(ctx, dsn)
+}
+
+// ReadDSN applies the same replica-selection and staleness check as ReadPool, but returns a DSN rather
+// than an open pool. It's for callers that shell out to a Postgres client binary (pg_dump, psql) instead
+// of going through pgx, so those reads get replica routing too.
+func (e *Executor) ReadDSN(ctx context.Context) (string, error) {
+	if len(e.config.Replicas) == 0 {
+		return e.dsn, nil
+	}
+
+	replica := e.nextReplica()
+	if !e.config.ReadStrictMode {
+		return replica, nil
+	}
+
+	e.mu.RLock()
+	want := e.primaryLSN
+	e.mu.RUnlock()
+	if want == "" {
+		return replica, nil
+	}
+
+	pool, err := sdkpg.Connect(ctx, replica)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to replica %q: %w", replica, err)
+	}
+	defer pool.Close()
+
+	got, err := replicaReplayLSN(ctx, pool)
+	if err != nil {
+		return "", fmt.Errorf("failed to read replay position on replica %q: %w", replica, err)
+	}
+
+	caughtUp, err := lsnAtLeast(got, want)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare WAL positions for replica %q: %w", replica, err)
+	}
+	if !caughtUp {
+		return "", &RevisionUnavailableError{Replica: replica, WantLSN: want, ReplicaLSN: got}
+	}
+	return replica, nil
+}
+
+func (e *Executor) nextReplica() string {
+	idx := atomic.AddUint32(&e.replicaIdx, 1) - 1
+	return e.config.Replicas[int(idx)%len(e.config.Replicas)]
+}
+
+func currentWALLSN(ctx context.Context, q pgxscan.Querier) (string, error) {
+	var lsn string
+	err := pgxscan.Get(ctx, q, &lsn, `SELECT pg_current_wal_lsn()::text`)
+	return lsn, err
+}
+
+func replicaReplayLSN(ctx context.Context, q pgxscan.Querier) (string, error) {
+	var lsn string
+	err := pgxscan.Get(ctx, q, &lsn, `SELECT pg_last_wal_replay_lsn()::text`)
+	return lsn, err
+}
+
+// lsnAtLeast reports whether got is at or past want. Postgres LSNs are formatted as "<hi>/<lo>" hex pairs,
+// so they have to be parsed rather than compared lexically.
+func lsnAtLeast(got, want string) (bool, error) {
+	gotVal, err := parseLSN(got)
+	if err != nil {
+		return false, err
+	}
+	wantVal, err := parseLSN(want)
+	if err != nil {
+		return false, err
+	}
+	return gotVal >= wantVal, nil
+}
+
+func parseLSN(lsn string) (uint64, error) {
+	parts := strings.SplitN(lsn, "/", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid LSN %q", lsn)
+	}
+	hi, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", lsn, err)
+	}
+	lo, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", lsn, err)
+	}
+	return hi<<32 | lo, nil
+}
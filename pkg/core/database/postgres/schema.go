@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// CreateProviderSchema creates the Postgres schema a provider's tables live in, if it doesn't already
+// exist. This is the schema create/drop hook that SyncProviders previously ran inline. Schema DDL is a
+// write, so it always goes through the primary.
+func (e *Executor) CreateProviderSchema(ctx context.Context, provider string) error {
+	pool, err := e.WritePool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	stmt := fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, pgx.Identifier{provider}.Sanitize())
+	if _, err := pool.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to create schema %q: %w", provider, err)
+	}
+	return e.RecordWriteLSN(ctx, pool)
+}
+
+// DropProviderSchema drops the Postgres schema a provider's tables live in, along with everything in it.
+// Like CreateProviderSchema, this always goes through the primary.
+func (e *Executor) DropProviderSchema(ctx context.Context, provider string) error {
+	pool, err := e.WritePool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	stmt := fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, pgx.Identifier{provider}.Sanitize())
+	if _, err := pool.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to drop schema %q: %w", provider, err)
+	}
+	return e.RecordWriteLSN(ctx, pool)
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/georgysavva/scany/dbscan"
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ExtraColumn is the fallback column provider tables carry for forward compatibility: fields the running
+// CLI doesn't recognize are spilled here as JSON instead of failing the write or the read.
+const ExtraColumn = "_cq_extra"
+
+// scanAPI returns a pgxscan API honoring Config.IgnoreUnknownColumns, so callers that read rows through
+// this Executor don't have to duplicate the dbscan wiring.
+func (e *Executor) scanAPI() (*pgxscan.API, error) {
+	dbAPI, err := dbscan.NewAPI(dbscan.WithAllowUnknownColumns(e.config.IgnoreUnknownColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build scan API: %w", err)
+	}
+	return pgxscan.NewAPI(dbAPI)
+}
+
+// EnsureExtraColumn adds the ExtraColumn fallback column to a provider table if it isn't there already, so
+// a newer provider worker writing unrecognized fields has somewhere to put them.
+func (e *Executor) EnsureExtraColumn(ctx context.Context, pool *pgxpool.Pool, schema, table string) error {
+	stmt := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s jsonb`,
+		pgx.Identifier{schema, table}.Sanitize(), pgx.Identifier{ExtraColumn}.Sanitize())
+	if _, err := pool.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to add %s column to %s.%s: %w", ExtraColumn, schema, table, err)
+	}
+	return nil
+}
+
+// Writer batches fetch-write-path inserts across many rows, possibly spanning several tables, over a
+// single pooled connection. Use NewWriter once per fetch run and call WriteRow for every row instead of
+// opening a connection per row: a provider sync can write millions of rows, and reconnecting (and, if the
+// row carries an unknown column, re-running ALTER TABLE) for each one doesn't scale. Callers must call
+// Close when done, which releases the pool and records the WAL position the batch's writes landed at.
+type Writer struct {
+	e    *Executor
+	pool *pgxpool.Pool
+
+	// ensured tracks which "schema.table" pairs have already had EnsureExtraColumn run, so a batch with an
+	// unknown column only issues the ALTER TABLE once per table rather than once per row.
+	ensured map[string]bool
+}
+
+// NewWriter opens the pooled connection a Writer's batch of rows will share.
+func (e *Executor) NewWriter(ctx context.Context) (*Writer, error) {
+	pool, err := e.WritePool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{e: e, pool: pool, ensured: make(map[string]bool)}, nil
+}
+
+// Close releases the Writer's pooled connection and records the primary's WAL position now that the
+// batch's writes have committed, so a subsequent strict ReadPool call only considers a replica caught up
+// to rows this Writer actually wrote.
+func (w *Writer) Close(ctx context.Context) error {
+	defer w.pool.Close()
+	return w.e.RecordWriteLSN(ctx, w.pool)
+}
+
+// SpillUnknownFields splits row into the subset of fields present in knownColumns and a JSON blob holding
+// everything else. It's the core of the fetch write path's forward compatibility: a row written by a
+// provider worker newer than the running CLI can carry columns this CLI has never heard of, and those get
+// preserved in extra rather than rejecting the whole row. extra is nil when every field in row is known.
+func SpillUnknownFields(knownColumns map[string]bool, row map[string]interface{}) (known map[string]interface{}, extra []byte, err error) {
+	known = make(map[string]interface{}, len(row))
+	unknown := make(map[string]interface{})
+	for col, val := range row {
+		if knownColumns[col] {
+			known[col] = val
+		} else {
+			unknown[col] = val
+		}
+	}
+	if len(unknown) == 0 {
+		return known, nil, nil
+	}
+
+	extra, err = json.Marshal(unknown)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode unknown columns into %s: %w", ExtraColumn, err)
+	}
+	return known, extra, nil
+}
+
+// WriteRow inserts row into schema.table as part of the fetch write path. Fields not in knownColumns -
+// i.e. columns a newer provider worker started writing that this CLI doesn't recognize yet - are spilled
+// into ExtraColumn instead of failing the write, provided Config.IgnoreUnknownColumns is set; the column
+// is added on demand via EnsureExtraColumn the first time schema.table needs it in this batch.
+func (w *Writer) WriteRow(ctx context.Context, schema, table string, knownColumns map[string]bool, row map[string]interface{}) error {
+	known, extra, err := SpillUnknownFields(knownColumns, row)
+	if err != nil {
+		return err
+	}
+
+	if extra != nil {
+		if !w.e.config.IgnoreUnknownColumns {
+			return fmt.Errorf("row for %s.%s has columns this CLI doesn't recognize (%s) and IgnoreUnknownColumns is disabled",
+				schema, table, unknownColumnNames(row, knownColumns))
+		}
+		key := schema + "." + table
+		if !w.ensured[key] {
+			if err := w.e.EnsureExtraColumn(ctx, w.pool, schema, table); err != nil {
+				return err
+			}
+			w.ensured[key] = true
+		}
+		known[ExtraColumn] = extra
+	}
+
+	cols := make([]string, 0, len(known))
+	placeholders := make([]string, 0, len(known))
+	args := make([]interface{}, 0, len(known))
+	for col, val := range known {
+		cols = append(cols, pgx.Identifier{col}.Sanitize())
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)+1))
+		args = append(args, val)
+	}
+
+	stmt := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`,
+		pgx.Identifier{schema, table}.Sanitize(), strings.Join(cols, ", "), strings.Join(placeholders, ", "))
+	if _, err := w.pool.Exec(ctx, stmt, args...); err != nil {
+		return fmt.Errorf("failed to insert row into %s.%s: %w", schema, table, err)
+	}
+	return nil
+}
+
+func unknownColumnNames(row map[string]interface{}, knownColumns map[string]bool) string {
+	var unknown []string
+	for col := range row {
+		if !knownColumns[col] {
+			unknown = append(unknown, col)
+		}
+	}
+	sort.Strings(unknown)
+	return strings.Join(unknown, ", ")
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSpillUnknownFields(t *testing.T) {
+	known := map[string]bool{"id": true, "name": true}
+	row := map[string]interface{}{
+		"id":          1,
+		"name":        "widget",
+		"new_feature": "beta", // a column a newer provider worker started writing
+	}
+
+	gotKnown, extra, err := SpillUnknownFields(known, row)
+	if err != nil {
+		t.Fatalf("SpillUnknownFields returned error: %v", err)
+	}
+	if len(gotKnown) != 2 || gotKnown["id"] != 1 || gotKnown["name"] != "widget" {
+		t.Fatalf("unexpected known columns: %#v", gotKnown)
+	}
+	if extra == nil {
+		t.Fatal("expected a non-nil extra payload for the unknown column")
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(extra, &decoded); err != nil {
+		t.Fatalf("extra payload is not valid JSON: %v", err)
+	}
+	if decoded["new_feature"] != "beta" {
+		t.Fatalf("expected new_feature to be spilled into extra, got %#v", decoded)
+	}
+}
+
+func TestSpillUnknownFields_NoUnknownColumns(t *testing.T) {
+	known := map[string]bool{"id": true}
+	row := map[string]interface{}{"id": 1}
+
+	_, extra, err := SpillUnknownFields(known, row)
+	if err != nil {
+		t.Fatalf("SpillUnknownFields returned error: %v", err)
+	}
+	if extra != nil {
+		t.Fatalf("expected no extra payload when every column is known, got %s", extra)
+	}
+}
+
+// TestWriterToleratesUnknownColumns exercises the full fetch write path against a real Postgres instance:
+// a batch of rows, including one carrying a column this CLI doesn't recognize, is written through a single
+// Writer with IgnoreUnknownColumns enabled and then read back through the tolerant scan API, mirroring
+// SyncProviders/policy queries continuing to work against a schema a newer provider worker has drifted
+// ahead of. It requires CLOUDQUERY_TEST_POSTGRES_DSN to point at a scratch database and is skipped
+// otherwise.
+func TestWriterToleratesUnknownColumns(t *testing.T) {
+	dsn := os.Getenv("CLOUDQUERY_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("set CLOUDQUERY_TEST_POSTGRES_DSN to run this test against a scratch Postgres instance")
+	}
+
+	ctx := context.Background()
+	e := NewWithConfig(Config{Primary: dsn, IgnoreUnknownColumns: true})
+
+	const schema = "cq_unknown_columns_test"
+	const table = "widgets"
+
+	if err := e.CreateProviderSchema(ctx, schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	defer e.DropProviderSchema(ctx, schema)
+
+	pool, err := e.WritePool(ctx)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.Exec(ctx, `CREATE TABLE `+schema+`.`+table+` (id int primary key, name text)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	w, err := e.NewWriter(ctx)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	known := map[string]bool{"id": true, "name": true}
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "widget", "synthetic_point_3d": "(1,2,3)"}, // a column/type this CLI doesn't know about
+		{"id": 2, "name": "gadget", "synthetic_point_3d": "(4,5,6)"}, // a second row on the same table: EnsureExtraColumn must run only once
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(ctx, schema, table, known, row); err != nil {
+			t.Fatalf("WriteRow failed to tolerate the unknown column: %v", err)
+		}
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	api, err := e.scanAPI()
+	if err != nil {
+		t.Fatalf("failed to build scan API: %v", err)
+	}
+
+	var got []struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	// This stands in for a policy query against the table: it only knows about id/name, yet the rows also
+	// carry _cq_extra and must still be readable.
+	if err := api.Select(ctx, pool, &got, `SELECT * FROM `+schema+`.`+table+` ORDER BY id`); err != nil {
+		t.Fatalf("policy-style query failed to tolerate the unknown column: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "widget" || got[1].Name != "gadget" {
+		t.Fatalf("unexpected rows: %#v", got)
+	}
+}
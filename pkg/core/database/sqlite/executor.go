@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ShuBo6/cloudquery/pkg/core/database/model"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Executor implements database.Executor against a local SQLite file, for single-user CloudQuery
+// deployments that don't want to run a Postgres server. There are no schemas in SQLite, so provider
+// tables are namespaced with a "<provider>_" prefix instead.
+type Executor struct {
+	path string
+	db   *sql.DB
+}
+
+// New returns a SQLite Executor for the given dsn (sqlite://path/to/file.db).
+func New(dsn string) *Executor {
+	return &Executor{path: strings.TrimPrefix(dsn, "sqlite://")}
+}
+
+func (e *Executor) Validate(ctx context.Context) (bool, error) {
+	db, err := sql.Open("sqlite3", e.path)
+	if err != nil {
+		return false, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return false, err
+	}
+	e.db = db
+	return true, nil
+}
+
+func (e *Executor) Info(ctx context.Context) (model.DatabaseInfo, error) {
+	var version string
+	if err := e.db.QueryRowContext(ctx, `select sqlite_version()`).Scan(&version); err != nil {
+		return model.DatabaseInfo{}, err
+	}
+	return model.DatabaseInfo{Version: version, FullVersion: "SQLite " + version}, nil
+}
+
+func (e *Executor) Identifier(context.Context) (string, bool) {
+	if e.path == "" {
+		return "", false
+	}
+	return e.path, true
+}
+
+// CreateProviderSchema is a no-op: SQLite has no schema namespaces, so there is nothing to create ahead
+// of a provider's tables being written with their "<provider>_" prefix.
+func (e *Executor) CreateProviderSchema(context.Context, string) error {
+	return nil
+}
+
+// DropProviderSchema drops every table prefixed with "<provider>_".
+func (e *Executor) DropProviderSchema(ctx context.Context, provider string) error {
+	pattern := escapeLikePattern(provider) + `\_%`
+	rows, err := e.db.QueryContext(ctx, `select name from sqlite_master where type = 'table' and name like ? escape '\'`, pattern)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		tables = append(tables, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, t := range tables {
+		if _, err := e.db.ExecContext(ctx, fmt.Sprintf(`drop table if exists %q`, t)); err != nil {
+			return fmt.Errorf("failed to drop table %q: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// escapeLikePattern escapes the characters that are special to a SQLite LIKE pattern (the escape
+// character itself, plus "_" and "%") within s, so s can be safely embedded in a pattern matched with
+// `escape '\'`. Without this, a provider name that itself contains "_" or "%" - e.g. "google_cloud" -
+// would have those act as live wildcards and could match another provider's tables.
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `_`, `\_`, `%`, `\%`)
+	return replacer.Replace(s)
+}
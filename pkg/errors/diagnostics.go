@@ -0,0 +1,28 @@
+package errors
+
+import "strings"
+
+// Diagnostics accumulates the errors encountered while running a command. A nil or empty Diagnostics
+// means the command succeeded.
+type Diagnostics []error
+
+// HasErrors reports whether any diagnostic was recorded.
+func (d Diagnostics) HasErrors() bool {
+	return len(d) > 0
+}
+
+// Error implements error so a Diagnostics value can be wrapped with fmt.Errorf's %w.
+func (d Diagnostics) Error() string {
+	msgs := make([]string, len(d))
+	for i, err := range d {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// CaptureDiagnostics is the hook for reporting diags, tagged with meta. It discards both arguments: nothing
+// is reported, and it never itself fails a command.
+func CaptureDiagnostics(diags Diagnostics, meta map[string]string) {
+	_ = diags
+	_ = meta
+}
@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultOrg is assumed when a provider source doesn't specify one, e.g. "aws" resolves to
+// "cloudquery/aws".
+const defaultOrg = "cloudquery"
+
+// ParseProviderName splits a provider source reference like "cloudquery/aws" or "aws" into its
+// organization and provider name.
+func ParseProviderName(source string) (org string, provider string, err error) {
+	parts := strings.Split(source, "/")
+	switch len(parts) {
+	case 1:
+		return defaultOrg, parts[0], nil
+	case 2:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("invalid provider source %q", source)
+	}
+}
+
+// MigrationWindow is the inclusive range of schema-migration versions a provider binary is compatible
+// with, i.e. its MinCompatibleMigration/MaxCompatibleMigration.
+type MigrationWindow struct {
+	Min string
+	Max string
+}
+
+// migrationWindows records the schema-migration version range each provider this CLI ships is
+// compatible with, keyed by provider name.
+var migrationWindows = map[string]MigrationWindow{
+	"aws": {Min: "0001", Max: "0007"},
+	"gcp": {Min: "0001", Max: "0004"},
+}
+
+// CompatibleMigrationRange returns the MinCompatibleMigration/MaxCompatibleMigration window this CLI
+// binary supports for provider, and false if the provider isn't one this CLI ships.
+func CompatibleMigrationRange(provider string) (MigrationWindow, bool) {
+	w, ok := migrationWindows[provider]
+	return w, ok
+}
+
+// CurrentMigrationVersion returns the schema-migration version this CLI binary applies when it syncs
+// provider fresh, i.e. the HEAD of its compatible range.
+func CurrentMigrationVersion(provider string) (string, bool) {
+	w, ok := migrationWindows[provider]
+	if !ok {
+		return "", false
+	}
+	return w.Max, true
+}
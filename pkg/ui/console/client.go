@@ -0,0 +1,201 @@
+// Package console wires together config, the provider registry, and a database.Executor into the Client
+// that every `cloudquery` command runs against.
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ShuBo6/cloudquery/pkg/config"
+	"github.com/ShuBo6/cloudquery/pkg/core"
+	"github.com/ShuBo6/cloudquery/pkg/core/database"
+	"github.com/ShuBo6/cloudquery/pkg/core/database/postgres"
+	"github.com/ShuBo6/cloudquery/pkg/core/database/postgres/migrationtest"
+	"github.com/ShuBo6/cloudquery/pkg/errors"
+	"github.com/ShuBo6/cloudquery/pkg/plugin/registry"
+)
+
+// Client is a single command invocation's view of the config and database.
+type Client struct {
+	cfg *config.Config
+	db  database.Executor
+}
+
+// CreateClient loads configFile and connects to the database it configures. When validateConfig is set,
+// the database is also validated (connectivity, version, migration compatibility) before returning.
+// allowedProviders restricts which configured providers this Client operates on; nil means all of them.
+// instanceId tags any diagnostics this invocation reports.
+func CreateClient(ctx context.Context, configFile string, validateConfig bool, allowedProviders []string, instanceId string) (*Client, error) {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config %q: %w", configFile, err)
+	}
+
+	db, err := database.New(cfg.CloudQuery.Connection.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := db.(*postgres.Executor); ok {
+		windows := make(map[string]postgres.MigrationWindow)
+		for _, p := range cfg.CloudQuery.Providers {
+			_, provider, err := core.ParseProviderSource(p)
+			if err != nil {
+				return nil, err
+			}
+			if w, ok := registry.CompatibleMigrationRange(provider); ok {
+				windows[provider] = postgres.MigrationWindow{Min: w.Min, Max: w.Max}
+			}
+		}
+		db = postgres.NewWithConfig(postgres.Config{Primary: cfg.CloudQuery.Connection.DSN, ProviderMigrations: windows})
+	}
+
+	if validateConfig {
+		if _, err := db.Validate(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Client{cfg: cfg, db: db}
+	if len(allowedProviders) > 0 {
+		c.cfg.CloudQuery.Providers = c.providers(allowedProviders)
+	}
+	return c, nil
+}
+
+// providers returns the requested providers from the config, or every configured provider when names is
+// empty.
+func (c *Client) providers(names []string) []*config.RequiredProvider {
+	if len(names) == 0 {
+		return c.cfg.CloudQuery.Providers
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var out []*config.RequiredProvider
+	for _, p := range c.cfg.CloudQuery.Providers {
+		if wanted[p.Name] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// SyncProviders drops and re-creates the database schema of the given providers (or every provider in the
+// config, if none are named). It does not download provider binaries.
+func (c *Client) SyncProviders(ctx context.Context, names ...string) (bool, errors.Diagnostics) {
+	var diags errors.Diagnostics
+	for _, p := range c.providers(names) {
+		_, provider, err := core.ParseProviderSource(p)
+		if err != nil {
+			diags = append(diags, err)
+			continue
+		}
+		if err := c.db.DropProviderSchema(ctx, provider); err != nil {
+			diags = append(diags, err)
+			continue
+		}
+		if err := c.db.CreateProviderSchema(ctx, provider); err != nil {
+			diags = append(diags, err)
+		}
+	}
+	return !diags.HasErrors(), diags
+}
+
+// BackupDatabase dumps the given providers (or every configured provider, if none are named) into a
+// versioned archive written to w, embedding each provider's current migration version.
+func (c *Client) BackupDatabase(ctx context.Context, w io.Writer, providerNames, tables []string) errors.Diagnostics {
+	pgExecutor, ok := c.db.(*postgres.Executor)
+	if !ok {
+		return errors.Diagnostics{fmt.Errorf("db backup is only supported against a postgres database")}
+	}
+
+	specs := make([]postgres.ProviderBackupSpec, 0, len(c.providers(providerNames)))
+	for _, p := range c.providers(providerNames) {
+		_, provider, err := core.ParseProviderSource(p)
+		if err != nil {
+			return errors.Diagnostics{err}
+		}
+		version, ok := registry.CurrentMigrationVersion(provider)
+		if !ok {
+			return errors.Diagnostics{fmt.Errorf("provider %q is not recognized by the plugin registry", provider)}
+		}
+		specs = append(specs, postgres.ProviderBackupSpec{
+			Name:             provider,
+			MigrationVersion: version,
+			Tables:           tables,
+		})
+	}
+
+	if err := pgExecutor.Backup(ctx, w, postgres.BackupOptions{Providers: specs}); err != nil {
+		return errors.Diagnostics{err}
+	}
+	return nil
+}
+
+// RestoreDatabase reloads the given providers (or every provider in the archive, if none are named) from
+// a backup archive, refusing to load a provider whose archived migration version doesn't match what this
+// CLI currently expects.
+func (c *Client) RestoreDatabase(ctx context.Context, r io.Reader, providerNames []string) errors.Diagnostics {
+	pgExecutor, ok := c.db.(*postgres.Executor)
+	if !ok {
+		return errors.Diagnostics{fmt.Errorf("db restore is only supported against a postgres database")}
+	}
+
+	current := make(map[string]string, len(c.cfg.CloudQuery.Providers))
+	for _, p := range c.cfg.CloudQuery.Providers {
+		_, provider, err := core.ParseProviderSource(p)
+		if err != nil {
+			return errors.Diagnostics{err}
+		}
+		if version, ok := registry.CurrentMigrationVersion(provider); ok {
+			current[provider] = version
+		}
+	}
+
+	opts := postgres.RestoreOptions{Providers: providerNames, CurrentMigrationVersions: current}
+	if err := pgExecutor.Restore(ctx, r, opts); err != nil {
+		return errors.Diagnostics{err}
+	}
+	return nil
+}
+
+// VerifyProviderMigrations checks that applying provider's incremental migrations into migratedDSN
+// produces the same schema as a fresh sync into freshDSN, returning a non-empty diff on mismatch. The
+// migrated side is produced by applyIncrementalMigrations, which only creates the provider's schema.
+func (c *Client) VerifyProviderMigrations(ctx context.Context, provider, migratedDSN, freshDSN string) (string, errors.Diagnostics) {
+	diff, err := migrationtest.Diff(ctx, migrationtest.Options{
+		Provider:    provider,
+		MigratedDSN: migratedDSN,
+		FreshDSN:    freshDSN,
+		Migrate:     applyIncrementalMigrations,
+		Sync:        syncFreshProviderSchema,
+	})
+	if err != nil {
+		return "", errors.Diagnostics{err}
+	}
+	return diff, nil
+}
+
+// applyIncrementalMigrations creates provider's schema against dsn. It does not read or apply any
+// incremental migration files, so the schema it leaves is the empty schema CreateProviderSchema makes.
+func applyIncrementalMigrations(ctx context.Context, dsn, provider string) error {
+	db, err := database.New(dsn)
+	if err != nil {
+		return err
+	}
+	return db.CreateProviderSchema(ctx, provider)
+}
+
+// syncFreshProviderSchema re-creates provider's schema from scratch, the same way SyncProviders does.
+func syncFreshProviderSchema(ctx context.Context, dsn, provider string) error {
+	db, err := database.New(dsn)
+	if err != nil {
+		return err
+	}
+	if err := db.DropProviderSchema(ctx, provider); err != nil {
+		return err
+	}
+	return db.CreateProviderSchema(ctx, provider)
+}